@@ -4,7 +4,10 @@ import (
 	"bytes"
 	"encoding/json"
 	"fmt"
+	"strconv"
 	"strings"
+	"time"
+	"unicode/utf8"
 
 	"github.com/hyperledger/fabric/core/chaincode/shim"
 	pb "github.com/hyperledger/fabric/protos/peer"
@@ -16,9 +19,25 @@ type SimpleChaincode struct {
 type FileDetails struct {
 	FileName string
 	FileHash string
+	Owner    string
+	Company  string
+}
+
+// FilePrivateDetails holds the fields that must stay restricted to the orgs named for
+// "collectionFiles" in collections_config.json. FileUrl (and any future access credentials)
+// lives here instead of in the public FileDetails so it never reaches the public ledger.
+type FilePrivateDetails struct {
+	FileName string
 	FileUrl  string
 }
 
+const filesPrivateCollection = "collectionFiles"
+
+// Composite index names, pulled out as constants so a typo in one call site is a compile
+// error instead of a silent mismatch against the index's writers.
+const hashNameIndex = "hash~name"
+const companyNameIndex = "company~name"
+
 // Init is called during chaincode instantiation to initialize any
 // data. Note that chaincode upgrade also calls this function to reset
 // or to migrate data.
@@ -36,6 +55,22 @@ func (t *SimpleChaincode) Invoke(stub shim.ChaincodeStubInterface) pb.Response {
 		return t.deleteFile(stub, args)
 	} else if function == "queryfile" { //find marbles based on an ad hoc rich query
 		return t.queryfile(stub, args)
+	} else if function == "transferFile" { //change the owner of a file
+		return t.transferFile(stub, args)
+	} else if function == "transferFilesByCompany" { //transfer all files belonging to a company to a new owner
+		return t.transferFilesByCompany(stub, args)
+	} else if function == "getFileHistory" { //get the provenance trail for a file
+		return t.getFileHistory(stub, args)
+	} else if function == "queryFileWithPagination" { //find files based on an ad hoc rich query, returned one page at a time
+		return t.queryFileWithPagination(stub, args)
+	} else if function == "getFilesByRange" { //find files whose keys fall within a range
+		return t.getFilesByRange(stub, args)
+	} else if function == "readFilePrivateDetails" { //read the private file url for an authorized org
+		return t.readFilePrivateDetails(stub, args)
+	} else if function == "initFilesBatch" { //create many files in a single transaction
+		return t.initFilesBatch(stub, args)
+	} else if function == "getFilesByHashPrefix" { //find files whose hash starts with a prefix
+		return t.getFilesByHashPrefix(stub, args)
 	}
 	fmt.Println("invoke did not find func: " + function) //error
 	return shim.Error("Received unknown function invocation")
@@ -45,9 +80,11 @@ func (t *SimpleChaincode) Invoke(stub shim.ChaincodeStubInterface) pb.Response {
 // initFile - create a new marble, store into chaincode state
 // ============================================================
 func (t *SimpleChaincode) initFile(stub shim.ChaincodeStubInterface, args []string) pb.Response {
-	//   0       		1     		  2
-	// "filename", "filehash", "fileurl",
-	if len(args) != 3 {
+	//   0       		1     		  2    		 3
+	// "filename", "filehash", "owner", "company"
+	// fileurl is not taken as a plaintext arg - it is read from the proposal's transient map
+	// below, which is not recorded in the transaction, so it never lands on the public ledger.
+	if len(args) != 4 {
 		return shim.Error("Incorrect number of arguments. Expecting 4")
 	}
 	// ==== Input sanitation ====
@@ -61,10 +98,28 @@ func (t *SimpleChaincode) initFile(stub shim.ChaincodeStubInterface, args []stri
 	if len(args[2]) <= 0 {
 		return shim.Error("3rd argument must be a non-empty string")
 	}
+	if len(args[3]) <= 0 {
+		return shim.Error("4th argument must be a non-empty string")
+	}
 
 	fileName := args[0]
 	fileHash := strings.ToLower(args[1])
-	fileUrl := strings.ToLower(args[3])
+	owner := args[2]
+	company := args[3]
+
+	// ==== Read the fileurl from the transient map ====
+	transMap, err := stub.GetTransient()
+	if err != nil {
+		return shim.Error("Error getting transient: " + err.Error())
+	}
+	fileUrlJSON, ok := transMap["file_url"]
+	if !ok {
+		return shim.Error("file_url must be a key in the transient map")
+	}
+	if len(fileUrlJSON) == 0 {
+		return shim.Error("file_url value in the transient map must be a non-empty string")
+	}
+	fileUrl := strings.ToLower(string(fileUrlJSON))
 
 	// ==== Check if file name  already exists ====
 	filenameAsBytes, err := stub.GetState(fileName)
@@ -75,7 +130,7 @@ func (t *SimpleChaincode) initFile(stub shim.ChaincodeStubInterface, args []stri
 		return shim.Error("This file is  already exists: " + fileName)
 	}
 	// ==== Create file object and marshal to JSON ====
-	filestore := &FileDetails{fileName, fileHash, fileUrl}
+	filestore := &FileDetails{fileName, fileHash, owner, company}
 	fileJSONasBytes, err := json.Marshal(filestore)
 	if err != nil {
 		return shim.Error(err.Error())
@@ -87,7 +142,18 @@ func (t *SimpleChaincode) initFile(stub shim.ChaincodeStubInterface, args []stri
 		return shim.Error(err.Error())
 	}
 
-	indexName := "hash~name"
+	// === Save the url to the private data collection ===
+	filePrivateDetails := &FilePrivateDetails{fileName, fileUrl}
+	filePrivateJSONasBytes, err := json.Marshal(filePrivateDetails)
+	if err != nil {
+		return shim.Error(err.Error())
+	}
+	err = stub.PutPrivateData(filesPrivateCollection, fileName, filePrivateJSONasBytes)
+	if err != nil {
+		return shim.Error(err.Error())
+	}
+
+	indexName := hashNameIndex
 	fileHashNameIndexKey, err := stub.CreateCompositeKey(indexName, []string{filestore.FileHash, filestore.FileName})
 	if err != nil {
 		return shim.Error(err.Error())
@@ -97,12 +163,209 @@ func (t *SimpleChaincode) initFile(stub shim.ChaincodeStubInterface, args []stri
 	value := []byte{0x00}
 	stub.PutState(fileHashNameIndexKey, value)
 
+	// maintain the company~name index so bulk transfers can iterate a company's files
+	companyNameIndexKey, err := stub.CreateCompositeKey(companyNameIndex, []string{filestore.Company, filestore.FileName})
+	if err != nil {
+		return shim.Error(err.Error())
+	}
+	stub.PutState(companyNameIndexKey, value)
+
 	// ==== file saved and indexed. Return success ====
 	fmt.Println("- end init filestored")
 	return shim.Success(nil)
 
 }
 
+// ===========================================================================================
+// initFilesBatch creates many files in a single transaction. Each PutState carries its own
+// endorsement/commit overhead, so registries that ingest many files at once (e.g. from a
+// directory hash) would otherwise need one proposal per file; batching amortizes that cost.
+// ===========================================================================================
+func (t *SimpleChaincode) initFilesBatch(stub shim.ChaincodeStubInterface, args []string) pb.Response {
+	//   0
+	// "[{FileName, FileHash, Owner, Company}, ...]"
+	if len(args) != 1 {
+		return shim.Error("Incorrect number of arguments. Expecting 1")
+	}
+
+	var files []FileDetails
+	err := json.Unmarshal([]byte(args[0]), &files)
+	if err != nil {
+		return shim.Error("Failed to unmarshal files batch: " + err.Error())
+	}
+	if len(files) == 0 {
+		return shim.Error("The files batch must not be empty")
+	}
+
+	// the transient map may carry "file_urls", a JSON object mapping FileName to FileUrl, so
+	// private details can be written for any entries that have one
+	fileUrls := make(map[string]string)
+	transMap, err := stub.GetTransient()
+	if err != nil {
+		return shim.Error("Error getting transient: " + err.Error())
+	}
+	if fileUrlsJSON, ok := transMap["file_urls"]; ok {
+		err = json.Unmarshal(fileUrlsJSON, &fileUrls)
+		if err != nil {
+			return shim.Error("Failed to unmarshal file_urls transient value: " + err.Error())
+		}
+	}
+
+	// ==== validate uniqueness, both within the batch and against existing state ====
+	seen := make(map[string]bool)
+	value := []byte{0x00}
+	for _, file := range files {
+		if len(file.FileName) <= 0 || len(file.FileHash) <= 0 || len(file.Owner) <= 0 || len(file.Company) <= 0 {
+			return shim.Error("Every entry must have a non-empty FileName, FileHash, Owner and Company")
+		}
+		if seen[file.FileName] {
+			return shim.Error("Duplicate filename in batch: " + file.FileName)
+		}
+		seen[file.FileName] = true
+
+		filenameAsBytes, err := stub.GetState(file.FileName)
+		if err != nil {
+			return shim.Error("Failed to get file: " + err.Error())
+		} else if filenameAsBytes != nil {
+			return shim.Error("This file already exists: " + file.FileName)
+		}
+	}
+
+	// ==== write all state and index keys ====
+	for _, file := range files {
+		file.FileHash = strings.ToLower(file.FileHash)
+
+		fileJSONasBytes, err := json.Marshal(file)
+		if err != nil {
+			return shim.Error(err.Error())
+		}
+		err = stub.PutState(file.FileName, fileJSONasBytes)
+		if err != nil {
+			return shim.Error(err.Error())
+		}
+
+		if fileUrl, ok := fileUrls[file.FileName]; ok {
+			filePrivateDetails := &FilePrivateDetails{file.FileName, strings.ToLower(fileUrl)}
+			filePrivateJSONasBytes, err := json.Marshal(filePrivateDetails)
+			if err != nil {
+				return shim.Error(err.Error())
+			}
+			err = stub.PutPrivateData(filesPrivateCollection, file.FileName, filePrivateJSONasBytes)
+			if err != nil {
+				return shim.Error(err.Error())
+			}
+		}
+
+		fileHashNameIndexKey, err := stub.CreateCompositeKey(hashNameIndex, []string{file.FileHash, file.FileName})
+		if err != nil {
+			return shim.Error(err.Error())
+		}
+		stub.PutState(fileHashNameIndexKey, value)
+
+		companyNameIndexKey, err := stub.CreateCompositeKey(companyNameIndex, []string{file.Company, file.FileName})
+		if err != nil {
+			return shim.Error(err.Error())
+		}
+		stub.PutState(companyNameIndexKey, value)
+	}
+
+	fmt.Printf("- end initFilesBatch, wrote %d files\n", len(files))
+	return shim.Success(nil)
+}
+
+// ===========================================================================================
+// getFilesByHashPrefix uses the hash~name composite index to find every file whose hash
+// starts with the given prefix. This enables duplicate-detection and find-by-hash without
+// paying for a CouchDB rich query, and works on LevelDB-backed peers too.
+//
+// GetStateByPartialCompositeKey only matches an attribute byte-for-byte (CreateCompositeKey
+// appends a trailing \x00 after every attribute, so "partial" means omitting trailing
+// attributes, not truncating the value of one), so a true prefix search has to be a raw range
+// scan bounded by hashPrefix and hashPrefix+maxUnicodeRune instead.
+// ===========================================================================================
+func (t *SimpleChaincode) getFilesByHashPrefix(stub shim.ChaincodeStubInterface, args []string) pb.Response {
+	//   0
+	// "hashPrefix"
+	if len(args) != 1 {
+		return shim.Error("Incorrect number of arguments. Expecting 1")
+	}
+
+	hashPrefix := strings.ToLower(args[0])
+
+	startKey, err := stub.CreateCompositeKey(hashNameIndex, []string{hashPrefix})
+	if err != nil {
+		return shim.Error(err.Error())
+	}
+	endKey, err := stub.CreateCompositeKey(hashNameIndex, []string{hashPrefix + string(utf8.MaxRune)})
+	if err != nil {
+		return shim.Error(err.Error())
+	}
+
+	hashNameResultsIterator, err := stub.GetStateByRange(startKey, endKey)
+	if err != nil {
+		return shim.Error(err.Error())
+	}
+	defer hashNameResultsIterator.Close()
+
+	var buffer bytes.Buffer
+	buffer.WriteString("[")
+
+	bArrayMemberAlreadyWritten := false
+	for hashNameResultsIterator.HasNext() {
+		responseRange, err := hashNameResultsIterator.Next()
+		if err != nil {
+			return shim.Error(err.Error())
+		}
+
+		_, compositeKeyParts, err := stub.SplitCompositeKey(responseRange.Key)
+		if err != nil {
+			return shim.Error(err.Error())
+		}
+		if !strings.HasPrefix(compositeKeyParts[0], hashPrefix) {
+			continue
+		}
+		returnedFileName := compositeKeyParts[1]
+
+		fileAsBytes, err := stub.GetState(returnedFileName)
+		if err != nil {
+			return shim.Error("Failed to get file: " + err.Error())
+		} else if fileAsBytes == nil {
+			continue
+		}
+
+		if bArrayMemberAlreadyWritten == true {
+			buffer.WriteString(",")
+		}
+		buffer.WriteString(string(fileAsBytes))
+		bArrayMemberAlreadyWritten = true
+	}
+	buffer.WriteString("]")
+
+	return shim.Success(buffer.Bytes())
+}
+
+// =========================================================================================
+// readFilePrivateDetails reads the private FileUrl for a filename from the collectionFiles
+// private data collection. Only peers belonging to an org named in collections_config.json
+// for that collection can see the result.
+// =========================================================================================
+func (t *SimpleChaincode) readFilePrivateDetails(stub shim.ChaincodeStubInterface, args []string) pb.Response {
+	//   0
+	// "filename"
+	if len(args) != 1 {
+		return shim.Error("Incorrect number of arguments. Expecting 1")
+	}
+
+	fileName := args[0]
+	filePrivateDetailsAsBytes, err := stub.GetPrivateData(filesPrivateCollection, fileName)
+	if err != nil {
+		return shim.Error("Failed to get private details for " + fileName + ": " + err.Error())
+	} else if filePrivateDetailsAsBytes == nil {
+		return shim.Error("No private details exist for: " + fileName)
+	}
+	return shim.Success(filePrivateDetailsAsBytes)
+}
+
 // ==================================================
 // delete - remove a marble key/value pair from state
 // ==================================================
@@ -136,7 +399,7 @@ func (t *SimpleChaincode) deleteFile(stub shim.ChaincodeStubInterface, args []st
 	}
 
 	// maintain the index
-	indexName := "Hash~name"
+	indexName := hashNameIndex
 	colorNameIndexKey, err := stub.CreateCompositeKey(indexName, []string{fileJSON.FileHash, fileJSON.FileName})
 	if err != nil {
 		return shim.Error(err.Error())
@@ -147,6 +410,136 @@ func (t *SimpleChaincode) deleteFile(stub shim.ChaincodeStubInterface, args []st
 	if err != nil {
 		return shim.Error("Failed to delete state:" + err.Error())
 	}
+
+	// maintain the company~name index
+	companyNameIndexKey, err := stub.CreateCompositeKey(companyNameIndex, []string{fileJSON.Company, fileJSON.FileName})
+	if err != nil {
+		return shim.Error(err.Error())
+	}
+	err = stub.DelState(companyNameIndexKey)
+	if err != nil {
+		return shim.Error("Failed to delete state:" + err.Error())
+	}
+
+	// remove the matching private details entry, if any
+	err = stub.DelPrivateData(filesPrivateCollection, filename)
+	if err != nil {
+		return shim.Error("Failed to delete private data:" + err.Error())
+	}
+	return shim.Success(nil)
+}
+
+// ===========================================================
+// transferFile - transfer a file to a new owner
+// ===========================================================
+func (t *SimpleChaincode) transferFile(stub shim.ChaincodeStubInterface, args []string) pb.Response {
+	//   0       		1
+	// "filename", "newowner"
+	if len(args) != 2 {
+		return shim.Error("Incorrect number of arguments. Expecting 2")
+	}
+	if len(args[0]) <= 0 {
+		return shim.Error("1st argument must be a non-empty string")
+	}
+	if len(args[1]) <= 0 {
+		return shim.Error("2nd argument must be a non-empty string")
+	}
+
+	fileName := args[0]
+	newOwner := args[1]
+	fmt.Println("- start transferFile ", fileName, newOwner)
+
+	fileAsBytes, err := stub.GetState(fileName)
+	if err != nil {
+		return shim.Error("Failed to get file: " + err.Error())
+	} else if fileAsBytes == nil {
+		return shim.Error("This file does not exist: " + fileName)
+	}
+
+	fileToTransfer := FileDetails{}
+	err = json.Unmarshal(fileAsBytes, &fileToTransfer)
+	if err != nil {
+		return shim.Error(err.Error())
+	}
+	fileToTransfer.Owner = newOwner //change the owner
+
+	fileJSONasBytes, err := json.Marshal(fileToTransfer)
+	if err != nil {
+		return shim.Error(err.Error())
+	}
+	err = stub.PutState(fileName, fileJSONasBytes) //rewrite the file
+	if err != nil {
+		return shim.Error(err.Error())
+	}
+
+	fmt.Println("- end transferFile (success)")
+	return shim.Success(nil)
+}
+
+// ==========================================================================================
+// transferFilesByCompany - transfer every file belonging to a company to a new owner, using
+// the company~name composite index so only that company's files are read from state
+// ==========================================================================================
+func (t *SimpleChaincode) transferFilesByCompany(stub shim.ChaincodeStubInterface, args []string) pb.Response {
+	//   0       		1
+	// "company", "newowner"
+	if len(args) != 2 {
+		return shim.Error("Incorrect number of arguments. Expecting 2")
+	}
+	if len(args[0]) <= 0 {
+		return shim.Error("1st argument must be a non-empty string")
+	}
+	if len(args[1]) <= 0 {
+		return shim.Error("2nd argument must be a non-empty string")
+	}
+
+	company := args[0]
+	newOwner := args[1]
+	fmt.Println("- start transferFilesByCompany ", company, newOwner)
+
+	companyNameResultsIterator, err := stub.GetStateByPartialCompositeKey(companyNameIndex, []string{company})
+	if err != nil {
+		return shim.Error(err.Error())
+	}
+	defer companyNameResultsIterator.Close()
+
+	for companyNameResultsIterator.HasNext() {
+		responseRange, err := companyNameResultsIterator.Next()
+		if err != nil {
+			return shim.Error(err.Error())
+		}
+
+		_, compositeKeyParts, err := stub.SplitCompositeKey(responseRange.Key)
+		if err != nil {
+			return shim.Error(err.Error())
+		}
+		returnedFileName := compositeKeyParts[1]
+
+		fileAsBytes, err := stub.GetState(returnedFileName)
+		if err != nil {
+			return shim.Error("Failed to get file: " + err.Error())
+		} else if fileAsBytes == nil {
+			continue
+		}
+
+		fileToTransfer := FileDetails{}
+		err = json.Unmarshal(fileAsBytes, &fileToTransfer)
+		if err != nil {
+			return shim.Error(err.Error())
+		}
+		fileToTransfer.Owner = newOwner
+
+		fileJSONasBytes, err := json.Marshal(fileToTransfer)
+		if err != nil {
+			return shim.Error(err.Error())
+		}
+		err = stub.PutState(returnedFileName, fileJSONasBytes)
+		if err != nil {
+			return shim.Error(err.Error())
+		}
+	}
+
+	fmt.Println("- end transferFilesByCompany (success)")
 	return shim.Success(nil)
 }
 
@@ -174,7 +567,169 @@ func (t *SimpleChaincode) queryfile(stub shim.ChaincodeStubInterface, args []str
 	return shim.Success(queryResults)
 }
 
-//start the chain code
+// ===========================================================================================
+// getFileHistory returns the full provenance trail for a file, i.e. every transaction that
+// ever wrote or deleted its state, mirroring the GetHistoryForKey pattern used by marbles02.
+// ===========================================================================================
+func (t *SimpleChaincode) getFileHistory(stub shim.ChaincodeStubInterface, args []string) pb.Response {
+	//   0
+	// "filename"
+	if len(args) != 1 {
+		return shim.Error("Incorrect number of arguments. Expecting 1")
+	}
+
+	fileName := args[0]
+	fmt.Println("- start getFileHistory: " + fileName)
+
+	resultsIterator, err := stub.GetHistoryForKey(fileName)
+	if err != nil {
+		return shim.Error(err.Error())
+	}
+	defer resultsIterator.Close()
+
+	buffer, err := constructHistoryResponseFromIterator(resultsIterator)
+	if err != nil {
+		return shim.Error(err.Error())
+	}
+
+	fmt.Printf("- getFileHistory returning:\n%s\n", buffer.String())
+	return shim.Success(buffer.Bytes())
+}
+
+// ===========================================================================================
+// constructHistoryResponseFromIterator constructs a JSON array of {TxId, Timestamp, IsDelete,
+// Value} entries from a history iterator. Value is the stored FileDetails JSON, or null for
+// a delete.
+// ===========================================================================================
+func constructHistoryResponseFromIterator(resultsIterator shim.HistoryQueryIteratorInterface) (*bytes.Buffer, error) {
+	var buffer bytes.Buffer
+	buffer.WriteString("[")
+
+	bArrayMemberAlreadyWritten := false
+	for resultsIterator.HasNext() {
+		response, err := resultsIterator.Next()
+		if err != nil {
+			return nil, err
+		}
+		if bArrayMemberAlreadyWritten == true {
+			buffer.WriteString(",")
+		}
+		buffer.WriteString("{\"TxId\":")
+		buffer.WriteString("\"")
+		buffer.WriteString(response.TxId)
+		buffer.WriteString("\"")
+
+		buffer.WriteString(", \"Timestamp\":")
+		buffer.WriteString("\"")
+		buffer.WriteString(time.Unix(response.Timestamp.Seconds, int64(response.Timestamp.Nanos)).UTC().Format(time.RFC3339))
+		buffer.WriteString("\"")
+
+		buffer.WriteString(", \"IsDelete\":")
+		buffer.WriteString(fmt.Sprintf("%t", response.IsDelete))
+
+		buffer.WriteString(", \"Value\":")
+		if response.IsDelete {
+			buffer.WriteString("null")
+		} else {
+			buffer.WriteString(string(response.Value))
+		}
+		buffer.WriteString("}")
+		bArrayMemberAlreadyWritten = true
+	}
+	buffer.WriteString("]")
+
+	return &buffer, nil
+}
+
+// ===========================================================================================
+// queryFileWithPagination uses a query string, page size and bookmark to perform a paginated
+// rich query. This avoids materializing the full result set into one buffer the way queryfile
+// does, so clients can page through large registries instead of risking an OOM.
+// =========================================================================================
+func (t *SimpleChaincode) queryFileWithPagination(stub shim.ChaincodeStubInterface, args []string) pb.Response {
+	//   0       		  1       		 2
+	// "queryString", "pageSize", "bookmark"
+	if len(args) != 3 {
+		return shim.Error("Incorrect number of arguments. Expecting 3")
+	}
+
+	queryString := args[0]
+	pageSize, err := strconv.ParseInt(args[1], 10, 32)
+	if err != nil {
+		return shim.Error(err.Error())
+	}
+	bookmark := args[2]
+
+	queryResults, err := getQueryResultForQueryStringWithPagination(stub, queryString, int32(pageSize), bookmark)
+	if err != nil {
+		return shim.Error(err.Error())
+	}
+	return shim.Success(queryResults)
+}
+
+// =========================================================================================
+// getQueryResultForQueryStringWithPagination executes the passed in query string with
+// pagination. The result set is wrapped together with the ResponseMetadata (fetched record
+// count and bookmark for the next page) in one JSON object.
+// =========================================================================================
+func getQueryResultForQueryStringWithPagination(stub shim.ChaincodeStubInterface, queryString string, pageSize int32, bookmark string) ([]byte, error) {
+
+	fmt.Printf("- getQueryResultForQueryStringWithPagination queryString:\n%s\n", queryString)
+
+	resultsIterator, responseMetadata, err := stub.GetQueryResultWithPagination(queryString, pageSize, bookmark)
+	if err != nil {
+		return nil, err
+	}
+	defer resultsIterator.Close()
+
+	buffer, err := constructQueryResponseFromIterator(resultsIterator)
+	if err != nil {
+		return nil, err
+	}
+
+	var paginatedBuffer bytes.Buffer
+	paginatedBuffer.WriteString("{\"Results\":")
+	paginatedBuffer.WriteString(buffer.String())
+	paginatedBuffer.WriteString(", \"ResponseMetadata\":")
+	paginatedBuffer.WriteString("{\"RecordsCount\":")
+	paginatedBuffer.WriteString(fmt.Sprintf("%d", responseMetadata.FetchedRecordsCount))
+	paginatedBuffer.WriteString(", \"Bookmark\":\"")
+	paginatedBuffer.WriteString(responseMetadata.Bookmark)
+	paginatedBuffer.WriteString("\"}}")
+
+	fmt.Printf("- getQueryResultForQueryStringWithPagination queryResult:\n%s\n", paginatedBuffer.String())
+
+	return paginatedBuffer.Bytes(), nil
+}
+
+// ===========================================================================================
+// getFilesByRange performs a range query, returning all files whose keys fall in
+// [startKey, endKey). Unlike queryfile this works on any state database, including LevelDB.
+// ===========================================================================================
+func (t *SimpleChaincode) getFilesByRange(stub shim.ChaincodeStubInterface, args []string) pb.Response {
+	//   0       		1
+	// "startKey", "endKey"
+	if len(args) != 2 {
+		return shim.Error("Incorrect number of arguments. Expecting 2")
+	}
+
+	startKey := args[0]
+	endKey := args[1]
+
+	resultsIterator, err := stub.GetStateByRange(startKey, endKey)
+	if err != nil {
+		return shim.Error(err.Error())
+	}
+	defer resultsIterator.Close()
+
+	buffer, err := constructQueryResponseFromIterator(resultsIterator)
+	if err != nil {
+		return shim.Error(err.Error())
+	}
+	return shim.Success(buffer.Bytes())
+}
+
+// start the chain code
 func main() {
 	err := shim.Start(new(SimpleChaincode))
 	if err != nil {